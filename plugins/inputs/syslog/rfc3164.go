@@ -0,0 +1,92 @@
+package syslog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rfc3164Message holds the fields of a parsed RFC3164 (BSD syslog) message.
+type rfc3164Message struct {
+	facility  int
+	severity  int
+	timestamp time.Time
+	hostname  string
+	appname   string
+	procID    string
+	message   string
+}
+
+// rfc3164TagRe matches the TAG[PID]: prefix that precedes the message content,
+// e.g. "sshd[1234]: " or "su: ".
+var rfc3164TagRe = regexp.MustCompile(`^([[:alnum:]_/.\-]+)(?:\[(\d+)\])?:\s?`)
+
+const rfc3164TimeLayout = "Jan _2 15:04:05"
+
+// parseRFC3164 parses a single BSD syslog (RFC3164) message. The year is not
+// part of the wire format, so it is derived from now, with a rollover
+// heuristic: a parsed date that lands more than a day in the future (as
+// happens for messages stamped "Dec 31" received shortly after midnight on
+// Jan 1) is assumed to belong to the previous year.
+func parseRFC3164(b []byte, now time.Time) (*rfc3164Message, error) {
+	s := strings.TrimRight(string(b), "\r\n")
+	if len(s) == 0 {
+		return nil, fmt.Errorf("rfc3164: empty message")
+	}
+
+	pri := 13 // facility=1 (user-level), severity=5 (notice) per RFC3164 default
+	if strings.HasPrefix(s, "<") {
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("rfc3164: missing closing '>' in priority")
+		}
+		p, err := strconv.Atoi(s[1:end])
+		if err != nil {
+			return nil, fmt.Errorf("rfc3164: invalid priority %q: %s", s[1:end], err)
+		}
+		pri = p
+		s = s[end+1:]
+	}
+
+	if len(s) < len(rfc3164TimeLayout) {
+		return nil, fmt.Errorf("rfc3164: message too short to contain a timestamp")
+	}
+	ts, err := time.ParseInLocation(rfc3164TimeLayout, s[:len(rfc3164TimeLayout)], now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("rfc3164: invalid timestamp %q: %s", s[:len(rfc3164TimeLayout)], err)
+	}
+	ts = time.Date(now.Year(), ts.Month(), ts.Day(), ts.Hour(), ts.Minute(), ts.Second(), 0, now.Location())
+	if ts.Sub(now) > 24*time.Hour {
+		ts = ts.AddDate(-1, 0, 0)
+	}
+	s = strings.TrimPrefix(s[len(rfc3164TimeLayout):], " ")
+
+	msg := &rfc3164Message{
+		facility:  pri / 8,
+		severity:  pri % 8,
+		timestamp: ts,
+	}
+
+	if !rfc3164TagRe.MatchString(s) {
+		// No TAG[PID]: right away, so the next token is the hostname.
+		sp := strings.IndexByte(s, ' ')
+		if sp < 0 {
+			msg.hostname = s
+			s = ""
+		} else {
+			msg.hostname = s[:sp]
+			s = s[sp+1:]
+		}
+	}
+
+	if m := rfc3164TagRe.FindStringSubmatch(s); m != nil {
+		msg.appname = m[1]
+		msg.procID = m[2]
+		s = s[len(m[0]):]
+	}
+	msg.message = s
+
+	return msg, nil
+}
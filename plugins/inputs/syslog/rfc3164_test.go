@@ -0,0 +1,110 @@
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRFC3164(t *testing.T) {
+	now := time.Date(2020, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    *rfc3164Message
+		wantErr bool
+	}{
+		{
+			name:  "hostname tag pid message",
+			input: "<34>Jun 15 10:00:00 myhost sshd[1234]: some message",
+			want: &rfc3164Message{
+				facility:  4,
+				severity:  2,
+				timestamp: time.Date(2020, time.June, 15, 10, 0, 0, 0, time.UTC),
+				hostname:  "myhost",
+				appname:   "sshd",
+				procID:    "1234",
+				message:   "some message",
+			},
+		},
+		{
+			name:  "no hostname, tag follows timestamp directly",
+			input: "<13>Jun 15 10:00:00 su: 'su root' failed for joe",
+			want: &rfc3164Message{
+				facility:  1,
+				severity:  5,
+				timestamp: time.Date(2020, time.June, 15, 10, 0, 0, 0, time.UTC),
+				hostname:  "",
+				appname:   "su",
+				procID:    "",
+				message:   "'su root' failed for joe",
+			},
+		},
+		{
+			name:  "no PRI uses RFC3164 default facility/severity",
+			input: "Jun 15 10:00:00 myhost some message with no tag",
+			want: &rfc3164Message{
+				facility:  1,
+				severity:  5,
+				timestamp: time.Date(2020, time.June, 15, 10, 0, 0, 0, time.UTC),
+				hostname:  "myhost",
+				message:   "some message with no tag",
+			},
+		},
+		{
+			name:    "malformed PRI missing closing bracket",
+			input:   "<34Jun 15 10:00:00 myhost sshd[1234]: some message",
+			wantErr: true,
+		},
+		{
+			name:    "malformed PRI not a number",
+			input:   "<abc>Jun 15 10:00:00 myhost sshd[1234]: some message",
+			wantErr: true,
+		},
+		{
+			name:    "empty message",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "too short to contain a timestamp",
+			input:   "<34>short",
+			wantErr: true,
+		},
+		{
+			name:  "new year rollover: Dec 31 message received shortly after midnight Jan 1",
+			input: "<34>Dec 31 23:59:00 myhost sshd[1234]: last message of the year",
+			want: &rfc3164Message{
+				facility:  4,
+				severity:  2,
+				timestamp: time.Date(2019, time.December, 31, 23, 59, 0, 0, time.UTC),
+				hostname:  "myhost",
+				appname:   "sshd",
+				procID:    "1234",
+				message:   "last message of the year",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rolloverNow time.Time
+			if tt.name == "new year rollover: Dec 31 message received shortly after midnight Jan 1" {
+				rolloverNow = time.Date(2020, time.January, 1, 0, 5, 0, 0, time.UTC)
+			} else {
+				rolloverNow = now
+			}
+
+			got, err := parseRFC3164([]byte(tt.input), rolloverNow)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
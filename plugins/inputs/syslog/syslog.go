@@ -1,6 +1,9 @@
 package syslog
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -9,6 +12,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/go-syslog/rfc5424"
@@ -20,23 +24,35 @@ import (
 )
 
 const defaultReadTimeout = time.Millisecond * 500
+const defaultShutdownTimeout = time.Second * 5
+const defaultMaxFrameSize = 64 * 1024
 const ipMaxPacketSize = 64 * 1024
+const statsInterval = 10 * time.Second
+const statsMeasurement = "syslog_conn"
 
 // Syslog is a syslog plugin
 type Syslog struct {
 	tlsConfig.ServerConfig
-	Address         string `toml:"server"`
-	KeepAlivePeriod *internal.Duration
-	ReadTimeout     *internal.Duration
-	MaxConnections  int
-	BestEffort      bool
-	Separator       string `toml:"sdparam_separator"`
+	Address              string `toml:"server"`
+	KeepAlivePeriod      *internal.Duration
+	ReadTimeout          *internal.Duration
+	ShutdownTimeout      *internal.Duration `toml:"shutdown_timeout"`
+	MaxConnections       int
+	BestEffort           bool
+	Separator            string `toml:"sdparam_separator"`
+	Standard             string `toml:"syslog_standard"`
+	Framing              string `toml:"framing"`
+	Trailer              string `toml:"trailer"`
+	MaxFrameSize         int    `toml:"max_frame_size"`
+	RequireTLSClientCert bool   `toml:"tls_require_client_cert"`
 
 	now      func() time.Time
 	lastTime time.Time
 
-	mu sync.Mutex
-	wg sync.WaitGroup
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
 	io.Closer
 
 	isStream      bool
@@ -46,6 +62,19 @@ type Syslog struct {
 	connectionsMu sync.Mutex
 
 	udpListener net.PacketConn
+
+	statsDone chan struct{}
+
+	// Connection lifecycle and parse-error counters, reported periodically
+	// as the "syslog_conn" measurement. Accessed atomically since they are
+	// updated from the listener goroutine and every per-connection handler.
+	connectionsActive      int64
+	connectionsTotal       int64
+	connectionsRejectedMax int64
+	tlsHandshakeErrors     int64
+	parseErrors            int64
+	messagesReceived       int64
+	bytesReceived          int64
 }
 
 var sampleConfig = `
@@ -60,6 +89,14 @@ var sampleConfig = `
   # tls_cert = "/etc/telegraf/cert.pem"
   # tls_key = "/etc/telegraf/key.pem"
 
+  ## Require and verify a client certificate for TLS connections (default =
+  ## false). Requires tls_allowed_cacerts to be set. When enabled, every
+  ## message parsed from the connection is tagged with client_cn, taken from
+  ## the peer certificate's subject common name, so a shared receiver can
+  ## attribute messages to the sending host cryptographically instead of
+  ## trusting the syslog hostname field.
+  # tls_require_client_cert = false
+
   ## Period between keep alive probes.
   ## 0 disables keep alive probes.
   ## Defaults to the OS configuration.
@@ -73,12 +110,51 @@ var sampleConfig = `
 
   ## Read timeout (default = 500ms).
   ## 0 means unlimited.
+  ## This is an idle timeout: it is reset on every frame read from the
+  ## connection, so it only fires on a connection that has gone quiet.
   # read_timeout = 500ms
 
+  ## Maximum time to wait for open connections to drain pending messages
+  ## when the plugin is stopped or reloaded (default = 5s). Connections
+  ## still open once this elapses are closed forcibly, discarding any
+  ## partially-buffered message.
+  ## Only applies to stream sockets (e.g. TCP).
+  # shutdown_timeout = "5s"
+
   ## Whether to parse in best effort mode or not (default = false).
   ## By default best effort parsing is off.
   # best_effort = false
 
+  ## Syslog message format to expect, either "RFC5424" (default) or
+  ## "RFC3164" (BSD syslog). RFC3164 messages carry no structured data or
+  ## message ID, and their timestamp has no year, so the current year is
+  ## assumed (with a rollover to the previous year for messages that would
+  ## otherwise land in the future, e.g. around New Year's).
+  ## On stream sockets (TLS included), RFC3164 messages are always read one
+  ## per line (LF-delimited); framing, trailer and max_frame_size do not
+  ## apply to them.
+  # syslog_standard = "RFC5424"
+
+  ## Message framing to use for plain-TCP (non-TLS) listeners, per RFC6587.
+  ## * "octet-counting" (default) expects each message prefixed with its
+  ##   length, e.g. "25 <14>1 ...". This is the only framing used for
+  ##   RFC5424 over TLS.
+  ## * "non-transparent" expects messages delimited by a trailing LF ("\n").
+  ## * "non-transparent-nul" expects messages delimited by a trailing NUL
+  ##   byte instead of LF.
+  # framing = "octet-counting"
+
+  ## Delimiter to use with non-transparent framing, either "LF" or "NUL".
+  ## When unset, the delimiter implied by framing (above) is used.
+  # trailer = "LF"
+
+  ## Maximum size in bytes of a single frame under non-transparent framing
+  ## (default = 65536). A connection that sends more than this many bytes
+  ## without the trailer is closed and the frame is discarded as a parse
+  ## error, bounding the memory a single sender can force the receiver to
+  ## buffer while withholding the trailer byte.
+  # max_frame_size = 65536
+
   ## Character to prepend to SD-PARAMs (default = "_").
   ## A syslog message can contain multiple parameters and multiple identifiers within structured data section.
   ## Eg., [id1 name1="val1" name2="val2"][id2 name1="val1" nameA="valA"]
@@ -94,7 +170,7 @@ func (s *Syslog) SampleConfig() string {
 
 // Description returns the plugin description
 func (s *Syslog) Description() string {
-	return "Accepts syslog messages per RFC5425"
+	return "Accepts syslog messages per RFC5425 or the legacy RFC3164 (BSD syslog) format"
 }
 
 // Gather ...
@@ -107,6 +183,8 @@ func (s *Syslog) Start(acc telegraf.Accumulator) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
 	scheme, host, err := getAddressParts(s.Address)
 	if err != nil {
 		return err
@@ -137,6 +215,18 @@ func (s *Syslog) Start(acc telegraf.Accumulator) error {
 		if err != nil {
 			return err
 		}
+		if s.tlsConfig != nil && s.isNonTransparent() {
+			return fmt.Errorf("framing %q is not supported over TLS (%s): TLS listeners always use octet-counting framing", s.Framing, s.Address)
+		}
+		if s.RequireTLSClientCert {
+			if s.tlsConfig == nil {
+				return fmt.Errorf("tls_require_client_cert requires tls_cert and tls_key to be set")
+			}
+			if len(s.TLSAllowedCACerts) == 0 {
+				return fmt.Errorf("tls_require_client_cert requires tls_allowed_cacerts to be set")
+			}
+			s.tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
 
 		s.wg.Add(1)
 		go s.listenStream(acc)
@@ -156,18 +246,87 @@ func (s *Syslog) Start(acc telegraf.Accumulator) error {
 		s.Closer = unixCloser{path: s.Address, closer: s.Closer}
 	}
 
+	s.statsDone = make(chan struct{})
+	s.wg.Add(1)
+	go s.emitStats(acc)
+
 	return nil
 }
 
-// Stop cleans up all resources
+// Stop cleans up all resources. It signals running handlers to wind down via
+// ctx, closes the listener, and gives open connections up to ShutdownTimeout
+// to drain their in-flight messages before force-closing them, so a reload
+// under load does not silently drop partially-buffered data.
 func (s *Syslog) Stop() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.cancel != nil {
+		s.cancel()
+	}
 	if s.Closer != nil {
 		s.Close()
 	}
-	s.wg.Wait()
+	if s.statsDone != nil {
+		close(s.statsDone)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	timeout := defaultShutdownTimeout
+	if s.ShutdownTimeout != nil {
+		timeout = s.ShutdownTimeout.Duration
+	}
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		s.connectionsMu.Lock()
+		for _, c := range s.connections {
+			c.Close()
+		}
+		s.connectionsMu.Unlock()
+		<-done
+	}
+}
+
+// emitStats periodically flushes connection lifecycle and parse-error
+// counters as the "syslog_conn" measurement, so operators can alert on a
+// receiver that is saturating MaxConnections or getting flooded with
+// malformed frames.
+func (s *Syslog) emitStats(acc telegraf.Accumulator) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flushStats(acc)
+		case <-s.statsDone:
+			s.flushStats(acc)
+			return
+		}
+	}
+}
+
+func (s *Syslog) flushStats(acc telegraf.Accumulator) {
+	tags := map[string]string{"address": s.Address}
+	fields := map[string]interface{}{
+		"connections_active":       atomic.LoadInt64(&s.connectionsActive),
+		"connections_total":        atomic.LoadInt64(&s.connectionsTotal),
+		"connections_rejected_max": atomic.LoadInt64(&s.connectionsRejectedMax),
+		"tls_handshake_errors":     atomic.LoadInt64(&s.tlsHandshakeErrors),
+		"parse_errors":             atomic.LoadInt64(&s.parseErrors),
+		"messages_received":        atomic.LoadInt64(&s.messagesReceived),
+		"bytes_received":           atomic.LoadInt64(&s.bytesReceived),
+	}
+	acc.AddFields(statsMeasurement, fields, tags, s.now())
 }
 
 // getAddressParts returns the address scheme and host
@@ -199,6 +358,12 @@ func getAddressParts(a string) (string, string, error) {
 	return u.Scheme, host, nil
 }
 
+// isRFC3164 reports whether the plugin is configured to parse BSD syslog
+// (RFC3164) messages instead of the default RFC5424.
+func (s *Syslog) isRFC3164() bool {
+	return strings.EqualFold(s.Standard, "RFC3164")
+}
+
 func (s *Syslog) listenPacket(acc telegraf.Accumulator) {
 	defer s.wg.Done()
 	b := make([]byte, ipMaxPacketSize)
@@ -215,12 +380,28 @@ func (s *Syslog) listenPacket(acc telegraf.Accumulator) {
 		if s.ReadTimeout != nil && s.ReadTimeout.Duration > 0 {
 			s.udpListener.SetReadDeadline(time.Now().Add(s.ReadTimeout.Duration))
 		}
+		atomic.AddInt64(&s.bytesReceived, int64(n))
+
+		if s.isRFC3164() {
+			message, err := parseRFC3164(b[:n], s.now())
+			if message != nil {
+				atomic.AddInt64(&s.messagesReceived, 1)
+				acc.AddFields("syslog", fieldsRFC3164(*message), tagsRFC3164(*message, ""), s.time())
+			}
+			if err != nil {
+				atomic.AddInt64(&s.parseErrors, 1)
+				acc.AddError(err)
+			}
+			continue
+		}
 
 		message, err := p.Parse(b[:n], &s.BestEffort)
 		if message != nil {
-			acc.AddFields("syslog", fields(*message, s), tags(*message), s.time())
+			atomic.AddInt64(&s.messagesReceived, 1)
+			acc.AddFields("syslog", fields(*message, s), tags(*message, ""), s.time())
 		}
 		if err != nil {
+			atomic.AddInt64(&s.parseErrors, 1)
 			acc.AddError(err)
 		}
 	}
@@ -239,6 +420,8 @@ func (s *Syslog) listenStream(acc telegraf.Accumulator) {
 			}
 			break
 		}
+		atomic.AddInt64(&s.connectionsTotal, 1)
+
 		var tcpConn, _ = conn.(*net.TCPConn)
 		if s.tlsConfig != nil {
 			conn = tls.Server(conn, s.tlsConfig)
@@ -247,54 +430,259 @@ func (s *Syslog) listenStream(acc telegraf.Accumulator) {
 		s.connectionsMu.Lock()
 		if s.MaxConnections > 0 && len(s.connections) >= s.MaxConnections {
 			s.connectionsMu.Unlock()
+			atomic.AddInt64(&s.connectionsRejectedMax, 1)
 			conn.Close()
 			continue
 		}
 		s.connections[conn.RemoteAddr().String()] = conn
 		s.connectionsMu.Unlock()
+		atomic.AddInt64(&s.connectionsActive, 1)
 
 		if err := s.setKeepAlive(tcpConn); err != nil {
 			acc.AddError(fmt.Errorf("unable to configure keep alive (%s): %s", s.Address, err))
 		}
 
+		s.wg.Add(1)
 		go s.handle(conn, acc)
 	}
 
-	s.connectionsMu.Lock()
-	for _, c := range s.connections {
-		c.Close()
-	}
-	s.connectionsMu.Unlock()
+	// Open connections are left running here so they can drain in-flight
+	// messages; Stop forcibly closes whatever remains once ShutdownTimeout
+	// elapses.
 }
 
 func (s *Syslog) removeConnection(c net.Conn) {
 	s.connectionsMu.Lock()
 	delete(s.connections, c.RemoteAddr().String())
 	s.connectionsMu.Unlock()
+	atomic.AddInt64(&s.connectionsActive, -1)
 }
 
 func (s *Syslog) handle(conn net.Conn, acc telegraf.Accumulator) {
 	defer func() {
 		s.removeConnection(conn)
 		conn.Close()
+		s.wg.Done()
 	}()
 
-	if s.ReadTimeout != nil && s.ReadTimeout.Duration > 0 {
-		conn.SetReadDeadline(time.Now().Add(s.ReadTimeout.Duration))
+	timeout := s.readTimeout()
+	if timeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+
+	var clientCN string
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		if err := tlsConn.Handshake(); err != nil {
+			atomic.AddInt64(&s.tlsHandshakeErrors, 1)
+			acc.AddError(fmt.Errorf("tls handshake (%s): %s", s.Address, err))
+			return
+		}
+
+		if s.RequireTLSClientCert {
+			certs := tlsConn.ConnectionState().PeerCertificates
+			if len(certs) == 0 {
+				atomic.AddInt64(&s.tlsHandshakeErrors, 1)
+				acc.AddError(fmt.Errorf("tls handshake (%s): no client certificate presented", s.Address))
+				return
+			}
+			clientCN = certs[0].Subject.CommonName
+		}
+	}
+
+	if s.isRFC3164() {
+		s.handleRFC3164(conn, acc, clientCN)
+		return
+	}
+
+	if s.isNonTransparent() {
+		s.handleNonTransparent(conn, acc, clientCN)
+		return
 	}
 
+	counted := countingReader{Reader: deadlineReader{conn: conn, timeout: timeout}, n: &s.bytesReceived}
 	var p *rfc5425.Parser
 	if s.BestEffort {
-		p = rfc5425.NewParser(conn, rfc5425.WithBestEffort())
+		p = rfc5425.NewParser(counted, rfc5425.WithBestEffort())
 	} else {
-		p = rfc5425.NewParser(conn)
+		p = rfc5425.NewParser(counted)
 	}
 
 	p.ParseExecuting(func(r *rfc5425.Result) {
-		s.store(*r, acc)
+		s.store(*r, acc, clientCN)
 	})
 }
 
+// readTimeout returns the configured ReadTimeout duration, or zero if unset.
+func (s *Syslog) readTimeout() time.Duration {
+	if s.ReadTimeout == nil {
+		return 0
+	}
+	return s.ReadTimeout.Duration
+}
+
+// countingReader wraps an io.Reader, atomically tallying the number of bytes
+// read into n, for the "bytes_received" connection metric.
+type countingReader struct {
+	io.Reader
+	n *int64
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		atomic.AddInt64(r.n, int64(n))
+	}
+	return n, err
+}
+
+// deadlineReader resets conn's read deadline to now+timeout before every
+// Read, so ReadTimeout behaves as an idle timeout between frames rather than
+// a hard cap on the connection's total lifetime.
+type deadlineReader struct {
+	conn    net.Conn
+	timeout time.Duration
+}
+
+func (r deadlineReader) Read(p []byte) (int, error) {
+	if r.timeout > 0 {
+		r.conn.SetReadDeadline(time.Now().Add(r.timeout))
+	}
+	return r.conn.Read(p)
+}
+
+// isNonTransparent reports whether the plugin is configured for one of the
+// RFC6587 non-transparent (delimiter-based) framings rather than the default
+// octet-counting framing used by RFC5425.
+func (s *Syslog) isNonTransparent() bool {
+	return s.Framing == "non-transparent" || s.Framing == "non-transparent-nul"
+}
+
+// nonTransparentTrailer returns the delimiter byte separating messages under
+// non-transparent framing: an explicit Trailer wins, otherwise it is implied
+// by Framing ("non-transparent-nul" means NUL, everything else means LF).
+func (s *Syslog) nonTransparentTrailer() byte {
+	switch strings.ToUpper(s.Trailer) {
+	case "NUL":
+		return 0
+	case "LF":
+		return '\n'
+	}
+	if s.Framing == "non-transparent-nul" {
+		return 0
+	}
+	return '\n'
+}
+
+// maxFrameSize returns the configured MaxFrameSize, or defaultMaxFrameSize if
+// unset.
+func (s *Syslog) maxFrameSize() int {
+	if s.MaxFrameSize > 0 {
+		return s.MaxFrameSize
+	}
+	return defaultMaxFrameSize
+}
+
+// splitOnTrailer returns a bufio.SplitFunc that tokenizes on trailer, the way
+// bufio.ScanLines tokenizes on '\n', so non-transparent framing can reuse
+// bufio.Scanner's bounded-buffer protection against an unterminated frame.
+func splitOnTrailer(trailer byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.IndexByte(data, trailer); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// handleNonTransparent reads RFC6587 non-transparent framed messages off
+// conn, each one delimited by nonTransparentTrailer, until EOF or an
+// unrecoverable read error. A frame larger than maxFrameSize without a
+// trailer closes the connection rather than growing the read buffer without
+// bound. clientCN, when non-empty, is the common name from the connection's
+// verified client certificate and is attached to every message as the
+// client_cn tag.
+func (s *Syslog) handleNonTransparent(conn net.Conn, acc telegraf.Accumulator, clientCN string) {
+	trailer := s.nonTransparentTrailer()
+	counted := countingReader{Reader: deadlineReader{conn: conn, timeout: s.readTimeout()}, n: &s.bytesReceived}
+	scanner := bufio.NewScanner(counted)
+	maxFrameSize := s.maxFrameSize()
+	// The buffer holds one frame of up to maxFrameSize bytes plus its trailer.
+	// bufio.Scanner.Buffer takes the larger of max and cap(buf) as the limit,
+	// so both are set to that same size; a buffer that fills up without a
+	// trailer means more than maxFrameSize bytes arrived for one frame.
+	bufSize := maxFrameSize + 1
+	scanner.Buffer(make([]byte, 0, bufSize), bufSize)
+	scanner.Split(splitOnTrailer(trailer))
+	p := rfc5424.NewParser()
+	for scanner.Scan() {
+		data := scanner.Bytes()
+		if len(data) > 0 {
+			message, perr := p.Parse(data, &s.BestEffort)
+			if message != nil {
+				atomic.AddInt64(&s.messagesReceived, 1)
+				acc.AddFields("syslog", fields(*message, s), tags(*message, clientCN), s.time())
+			}
+			if perr != nil {
+				atomic.AddInt64(&s.parseErrors, 1)
+				acc.AddError(perr)
+			}
+		}
+		// Checked after the frame is delivered, not before scanning it, so a
+		// frame already buffered ahead of a Stop() cancellation is still
+		// processed instead of silently dropped.
+		if s.ctx.Err() != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			atomic.AddInt64(&s.parseErrors, 1)
+			acc.AddError(fmt.Errorf("rfc6587 frame exceeds max_frame_size=%d (%s): closing connection", maxFrameSize, s.Address))
+		} else if !strings.HasSuffix(err.Error(), "use of closed network connection") {
+			acc.AddError(err)
+		}
+	}
+}
+
+// handleRFC3164 reads newline-delimited BSD syslog messages off conn until
+// EOF, an unrecoverable read error, or (unless BestEffort is set) the first
+// message that fails to parse. clientCN, when non-empty, is the
+// common name from the connection's verified client certificate and is
+// attached to every message as the client_cn tag.
+func (s *Syslog) handleRFC3164(conn net.Conn, acc telegraf.Accumulator, clientCN string) {
+	counted := countingReader{Reader: deadlineReader{conn: conn, timeout: s.readTimeout()}, n: &s.bytesReceived}
+	scanner := bufio.NewScanner(counted)
+	for scanner.Scan() {
+		message, err := parseRFC3164(scanner.Bytes(), s.now())
+		if message != nil {
+			atomic.AddInt64(&s.messagesReceived, 1)
+			acc.AddFields("syslog", fieldsRFC3164(*message), tagsRFC3164(*message, clientCN), s.time())
+		}
+		if err != nil {
+			atomic.AddInt64(&s.parseErrors, 1)
+			acc.AddError(err)
+			if !s.BestEffort {
+				return
+			}
+		}
+		// Checked after the frame is delivered, not before scanning it, so a
+		// frame already buffered ahead of a Stop() cancellation is still
+		// processed instead of silently dropped.
+		if s.ctx.Err() != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && !strings.HasSuffix(err.Error(), "use of closed network connection") {
+		acc.AddError(err)
+	}
+}
+
 func (s *Syslog) setKeepAlive(c *net.TCPConn) error {
 	if s.KeepAlivePeriod == nil {
 		return nil
@@ -309,20 +697,25 @@ func (s *Syslog) setKeepAlive(c *net.TCPConn) error {
 	return c.SetKeepAlivePeriod(s.KeepAlivePeriod.Duration)
 }
 
-func (s *Syslog) store(res rfc5425.Result, acc telegraf.Accumulator) {
+func (s *Syslog) store(res rfc5425.Result, acc telegraf.Accumulator, clientCN string) {
 	if res.Error != nil {
+		atomic.AddInt64(&s.parseErrors, 1)
 		acc.AddError(res.Error)
 	}
 	if res.MessageError != nil {
+		atomic.AddInt64(&s.parseErrors, 1)
 		acc.AddError(res.MessageError)
 	}
 	if res.Message != nil {
+		atomic.AddInt64(&s.messagesReceived, 1)
 		msg := *res.Message
-		acc.AddFields("syslog", fields(msg, s), tags(msg), s.time())
+		acc.AddFields("syslog", fields(msg, s), tags(msg, clientCN), s.time())
 	}
 }
 
-func tags(msg rfc5424.SyslogMessage) map[string]string {
+// tags builds the tag set for msg. clientCN, when non-empty, is the common
+// name from the sending connection's verified client certificate.
+func tags(msg rfc5424.SyslogMessage, clientCN string) map[string]string {
 	ts := map[string]string{}
 
 	// Not checking assuming a minimally valid message
@@ -337,6 +730,10 @@ func tags(msg rfc5424.SyslogMessage) map[string]string {
 		ts["appname"] = *msg.Appname()
 	}
 
+	if clientCN != "" {
+		ts["client_cn"] = clientCN
+	}
+
 	return ts
 }
 
@@ -381,6 +778,70 @@ func fields(msg rfc5424.SyslogMessage, s *Syslog) map[string]interface{} {
 	return flds
 }
 
+// tagsRFC3164 builds the tag set for msg. clientCN, when non-empty, is the
+// common name from the sending connection's verified client certificate.
+func tagsRFC3164(msg rfc3164Message, clientCN string) map[string]string {
+	ts := map[string]string{}
+
+	ts["severity"] = severityShortLevel(msg.severity)
+	ts["facility"] = facilityLevel(msg.facility)
+
+	if msg.hostname != "" {
+		ts["hostname"] = msg.hostname
+	}
+
+	if msg.appname != "" {
+		ts["appname"] = msg.appname
+	}
+
+	if clientCN != "" {
+		ts["client_cn"] = clientCN
+	}
+
+	return ts
+}
+
+func fieldsRFC3164(msg rfc3164Message) map[string]interface{} {
+	flds := map[string]interface{}{
+		"severity_code": msg.severity,
+		"facility_code": msg.facility,
+		"timestamp":     msg.timestamp.UnixNano(),
+		"message":       msg.message,
+	}
+
+	if msg.procID != "" {
+		flds["procid"] = msg.procID
+	}
+
+	return flds
+}
+
+// severityShortLevel maps an RFC3164 severity code to the same short level
+// strings rfc5424.SyslogMessage.SeverityShortLevel() produces, so RFC3164 and
+// RFC5424 messages tag identically.
+func severityShortLevel(severity int) string {
+	levels := []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+	if severity < 0 || severity >= len(levels) {
+		return ""
+	}
+	return levels[severity]
+}
+
+// facilityLevel maps an RFC3164 facility code to the keyword names used by
+// rfc5424.SyslogMessage.FacilityLevel(), so RFC3164 and RFC5424 messages tag
+// identically.
+func facilityLevel(facility int) string {
+	levels := []string{
+		"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+		"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+		"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+	}
+	if facility < 0 || facility >= len(levels) {
+		return ""
+	}
+	return levels[facility]
+}
+
 type unixCloser struct {
 	path   string
 	closer io.Closer
@@ -412,7 +873,12 @@ func init() {
 		ReadTimeout: &internal.Duration{
 			Duration: defaultReadTimeout,
 		},
+		ShutdownTimeout: &internal.Duration{
+			Duration: defaultShutdownTimeout,
+		},
 		Separator: "_",
+		Standard:  "RFC5424",
+		Framing:   "octet-counting",
 	}
 
 	inputs.Add("syslog", func() telegraf.Input { return receiver })
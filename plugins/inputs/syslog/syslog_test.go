@@ -0,0 +1,299 @@
+package syslog
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTestSyslog() *Syslog {
+	return &Syslog{
+		Address:   "tcp://127.0.0.1:0",
+		now:       getNanoNow,
+		Separator: "_",
+		Standard:  "RFC5424",
+		Framing:   "octet-counting",
+		ReadTimeout: &internal.Duration{
+			Duration: time.Second,
+		},
+		ShutdownTimeout: &internal.Duration{
+			Duration: time.Second,
+		},
+	}
+}
+
+func TestStartStopOctetCounting(t *testing.T) {
+	s := newTestSyslog()
+	acc := &testutil.Accumulator{}
+	require.NoError(t, s.Start(acc))
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.tcpListener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	msg := "<14>1 2021-01-01T00:00:00Z myhost myapp - - - hello world"
+	_, err = fmt.Fprintf(conn, "%d %s", len(msg), msg)
+	require.NoError(t, err)
+
+	acc.Wait(1)
+	acc.AssertContainsTaggedFields(t, "syslog",
+		map[string]interface{}{
+			"version":       uint16(1),
+			"severity_code": 6,
+			"facility_code": 1,
+			"message":       "hello world",
+		},
+		map[string]string{
+			"severity": "info",
+			"facility": "user",
+			"hostname": "myhost",
+			"appname":  "myapp",
+		},
+	)
+}
+
+func TestStartStopNonTransparent(t *testing.T) {
+	s := newTestSyslog()
+	s.Framing = "non-transparent"
+	acc := &testutil.Accumulator{}
+	require.NoError(t, s.Start(acc))
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.tcpListener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	msg := "<14>1 2021-01-01T00:00:00Z myhost myapp - - - hello world"
+	_, err = fmt.Fprintf(conn, "%s\n", msg)
+	require.NoError(t, err)
+
+	acc.Wait(1)
+	acc.AssertContainsTaggedFields(t, "syslog",
+		map[string]interface{}{
+			"message": "hello world",
+		},
+		map[string]string{
+			"hostname": "myhost",
+		},
+	)
+}
+
+func TestNonTransparentOversizedFrameIsRejected(t *testing.T) {
+	s := newTestSyslog()
+	s.Framing = "non-transparent"
+	s.MaxFrameSize = 16
+	// Long enough that the assertion below can only be explained by
+	// max_frame_size enforcement, not by the idle read_timeout expiring.
+	s.ReadTimeout = &internal.Duration{Duration: time.Minute}
+	acc := &testutil.Accumulator{}
+	require.NoError(t, s.Start(acc))
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.tcpListener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Never send the trailer, and keep writing well past max_frame_size
+	// with no idle gap, so without a cap this would grow the read buffer
+	// without bound.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		chunk := []byte(strings.Repeat("x", 1024))
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := conn.Write(chunk); err != nil {
+				return
+			}
+		}
+	}()
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&s.parseErrors) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	acc.Lock()
+	defer acc.Unlock()
+	require.NotEmpty(t, acc.Errors)
+	assert.Contains(t, acc.Errors[len(acc.Errors)-1].Error(), "max_frame_size")
+}
+
+func TestSyslogConnStats(t *testing.T) {
+	s := newTestSyslog()
+	acc := &testutil.Accumulator{}
+	require.NoError(t, s.Start(acc))
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.tcpListener.Addr().String())
+	require.NoError(t, err)
+	msg := "<14>1 2021-01-01T00:00:00Z myhost myapp - - - hello world"
+	_, err = fmt.Fprintf(conn, "%d %s", len(msg), msg)
+	require.NoError(t, err)
+	acc.Wait(1)
+	conn.Close()
+
+	// Wait for the handle() goroutine to observe the close and remove the
+	// connection before reading connections_active, since that happens
+	// asynchronously with respect to conn.Close() returning.
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&s.connectionsActive) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.flushStats(acc)
+	acc.Wait(2)
+	acc.AssertContainsFields(t, statsMeasurement, map[string]interface{}{
+		"connections_active":       int64(0),
+		"connections_total":        int64(1),
+		"connections_rejected_max": int64(0),
+		"tls_handshake_errors":     int64(0),
+		"parse_errors":             int64(0),
+		"messages_received":        int64(1),
+		"bytes_received":           int64(len(fmt.Sprintf("%d %s", len(msg), msg))),
+	})
+}
+
+func TestRequireTLSClientCertRejectsHandshakeWithoutCert(t *testing.T) {
+	caCertPath, serverCertPath, serverKeyPath, cleanup := generateTestCerts(t)
+	defer cleanup()
+
+	s := newTestSyslog()
+	s.TLSCert = serverCertPath
+	s.TLSKey = serverKeyPath
+	s.TLSAllowedCACerts = []string{caCertPath}
+	s.RequireTLSClientCert = true
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, s.Start(acc))
+	defer s.Stop()
+
+	caPEM, err := os.ReadFile(caCertPath)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(caPEM))
+
+	conn, err := tls.Dial("tcp", s.tcpListener.Addr().String(), &tls.Config{
+		RootCAs:    pool,
+		ServerName: "localhost",
+	})
+	if err == nil {
+		defer conn.Close()
+		// Some TLS versions complete the client-side handshake before the
+		// server's rejection is visible; a subsequent read must still fail.
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		buf := make([]byte, 1)
+		_, err = conn.Read(buf)
+	}
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&s.tlsHandshakeErrors) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestStartRejectsNonTransparentFramingOverTLS(t *testing.T) {
+	caCertPath, serverCertPath, serverKeyPath, cleanup := generateTestCerts(t)
+	defer cleanup()
+
+	s := newTestSyslog()
+	s.Framing = "non-transparent"
+	s.TLSCert = serverCertPath
+	s.TLSKey = serverKeyPath
+	s.TLSAllowedCACerts = []string{caCertPath}
+
+	acc := &testutil.Accumulator{}
+	err := s.Start(acc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "octet-counting")
+}
+
+func TestRequireTLSClientCertWithoutAllowedCACertsFailsToStart(t *testing.T) {
+	_, serverCertPath, serverKeyPath, cleanup := generateTestCerts(t)
+	defer cleanup()
+
+	s := newTestSyslog()
+	s.TLSCert = serverCertPath
+	s.TLSKey = serverKeyPath
+	s.RequireTLSClientCert = true
+
+	acc := &testutil.Accumulator{}
+	err := s.Start(acc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tls_allowed_cacerts")
+}
+
+// generateTestCerts creates a throwaway CA and a server certificate signed by
+// it, writes them to temp PEM files, and returns their paths along with a
+// cleanup func.
+func generateTestCerts(t *testing.T) (caCertPath, serverCertPath, serverKeyPath string, cleanup func()) {
+	t.Helper()
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caCert, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caCertFile, err := os.CreateTemp(t.TempDir(), "ca-*.pem")
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(caCertFile, &pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+	require.NoError(t, caCertFile.Close())
+
+	serverCertFile, err := os.CreateTemp(t.TempDir(), "server-cert-*.pem")
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(serverCertFile, &pem.Block{Type: "CERTIFICATE", Bytes: serverDER}))
+	require.NoError(t, serverCertFile.Close())
+
+	serverKeyFile, err := os.CreateTemp(t.TempDir(), "server-key-*.pem")
+	require.NoError(t, err)
+	serverKeyDER := x509.MarshalPKCS1PrivateKey(serverKey)
+	require.NoError(t, pem.Encode(serverKeyFile, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: serverKeyDER}))
+	require.NoError(t, serverKeyFile.Close())
+
+	return caCertFile.Name(), serverCertFile.Name(), serverKeyFile.Name(), func() {}
+}